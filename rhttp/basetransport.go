@@ -0,0 +1,211 @@
+package rhttp
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+// BaseTransportConfig tunes the *http.Transport rhttp builds for
+// WithDefaultBaseTransport, covering the dial and connection-pool knobs
+// that otherwise require hand-rolling an http.Transport before passing it
+// to WithBaseTransport.
+type BaseTransportConfig struct {
+	// DialTimeout bounds establishing the TCP connection. Defaults to 30s.
+	DialTimeout time.Duration
+	// KeepAlive sets the TCP keep-alive period. Defaults to 30s.
+	KeepAlive time.Duration
+
+	// MaxIdleConns caps idle connections across all hosts. Defaults to 100.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps idle connections per host. Defaults to 10.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps total (idle + active) connections per host. Zero
+	// means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout closes idle connections after this long. Defaults to
+	// 90s.
+	IdleConnTimeout time.Duration
+
+	// TLSHandshakeTimeout bounds the TLS handshake. Defaults to 10s.
+	TLSHandshakeTimeout time.Duration
+	// ExpectContinueTimeout bounds waiting for a 100-continue response.
+	// Defaults to 1s.
+	ExpectContinueTimeout time.Duration
+	// ResponseHeaderTimeout bounds waiting for response headers once the
+	// request has been written. Zero means no timeout.
+	ResponseHeaderTimeout time.Duration
+
+	// ForceAttemptHTTP2 enables opportunistic HTTP/2 via the stock
+	// transport's TLS-ALPN negotiation. Defaults to true; since
+	// newBaseTransport always sets DialContext, the stdlib transport won't
+	// enable HTTP/2 on its own without this. A non-nil pointer overrides the
+	// default, including to explicitly disable it.
+	ForceAttemptHTTP2 *bool
+}
+
+func (cfg BaseTransportConfig) dialTimeout() time.Duration {
+	if cfg.DialTimeout > 0 {
+		return cfg.DialTimeout
+	}
+	return 30 * time.Second
+}
+
+func (cfg BaseTransportConfig) keepAlive() time.Duration {
+	if cfg.KeepAlive > 0 {
+		return cfg.KeepAlive
+	}
+	return 30 * time.Second
+}
+
+func (cfg BaseTransportConfig) maxIdleConns() int {
+	if cfg.MaxIdleConns > 0 {
+		return cfg.MaxIdleConns
+	}
+	return 100
+}
+
+func (cfg BaseTransportConfig) maxIdleConnsPerHost() int {
+	if cfg.MaxIdleConnsPerHost > 0 {
+		return cfg.MaxIdleConnsPerHost
+	}
+	return 10
+}
+
+func (cfg BaseTransportConfig) idleConnTimeout() time.Duration {
+	if cfg.IdleConnTimeout > 0 {
+		return cfg.IdleConnTimeout
+	}
+	return 90 * time.Second
+}
+
+func (cfg BaseTransportConfig) tlsHandshakeTimeout() time.Duration {
+	if cfg.TLSHandshakeTimeout > 0 {
+		return cfg.TLSHandshakeTimeout
+	}
+	return 10 * time.Second
+}
+
+func (cfg BaseTransportConfig) expectContinueTimeout() time.Duration {
+	if cfg.ExpectContinueTimeout > 0 {
+		return cfg.ExpectContinueTimeout
+	}
+	return time.Second
+}
+
+func (cfg BaseTransportConfig) forceAttemptHTTP2() bool {
+	if cfg.ForceAttemptHTTP2 == nil {
+		return true
+	}
+	return *cfg.ForceAttemptHTTP2
+}
+
+// newBaseTransport builds a *http.Transport tuned per cfg, mirroring
+// http.DefaultTransport's defaults except where cfg overrides them and for
+// MaxIdleConnsPerHost, which defaults to 10 rather than the stdlib's 2.
+func newBaseTransport(cfg BaseTransportConfig) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   cfg.dialTimeout(),
+		KeepAlive: cfg.keepAlive(),
+	}
+
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		MaxIdleConns:          cfg.maxIdleConns(),
+		MaxIdleConnsPerHost:   cfg.maxIdleConnsPerHost(),
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.idleConnTimeout(),
+		TLSHandshakeTimeout:   cfg.tlsHandshakeTimeout(),
+		ExpectContinueTimeout: cfg.expectContinueTimeout(),
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ForceAttemptHTTP2:     cfg.forceAttemptHTTP2(),
+	}
+}
+
+// WithDefaultBaseTransport replaces the base transport with a
+// *http.Transport tuned per cfg, so callers get sensible connection-pool
+// defaults without hand-rolling one themselves.
+func WithDefaultBaseTransport(cfg BaseTransportConfig) Option {
+	return func(c *Config) { c.Base = newBaseTransport(cfg) }
+}
+
+// HTTP2Config tunes HTTP/2-specific behavior on top of the base transport.
+type HTTP2Config struct {
+	Enabled bool
+
+	// ReadIdleTimeout is the interval at which health-check pings are sent
+	// on an otherwise-idle HTTP/2 connection, so a dead connection behind a
+	// load balancer is detected instead of stalling a request. Defaults to
+	// 30s.
+	ReadIdleTimeout time.Duration
+	// PingTimeout bounds waiting for a health-check ping's ACK before the
+	// connection is considered dead. Defaults to 15s.
+	PingTimeout time.Duration
+	// WriteByteTimeout bounds how long a write to the connection may take
+	// before rhttp gives up and closes it. Zero means no timeout.
+	WriteByteTimeout time.Duration
+	// MaxHeaderListSize caps the size of the header list the peer is
+	// permitted to send. Zero means use the http2 package's default.
+	MaxHeaderListSize uint32
+}
+
+func (cfg HTTP2Config) readIdleTimeout() time.Duration {
+	if cfg.ReadIdleTimeout > 0 {
+		return cfg.ReadIdleTimeout
+	}
+	return 30 * time.Second
+}
+
+func (cfg HTTP2Config) pingTimeout() time.Duration {
+	if cfg.PingTimeout > 0 {
+		return cfg.PingTimeout
+	}
+	return 15 * time.Second
+}
+
+// WithHTTP2 enables and tunes HTTP/2 support on the base transport. rhttp
+// applies it once all options have been processed, so WithHTTP2 may be
+// passed before or after WithBaseTransport/WithDefaultBaseTransport; it only
+// takes effect when the resulting base transport is a *http.Transport.
+func WithHTTP2(cfg HTTP2Config) Option {
+	return func(c *Config) { c.HTTP2 = cfg }
+}
+
+// configureHTTP2 applies cfg.HTTP2 to c.Base if enabled and c.Base is a
+// *http.Transport. Errors are deliberately swallowed: ConfigureTransports
+// only fails when the base transport's TLS configuration is already
+// incompatible with HTTP/2, which rhttp cannot repair on the caller's
+// behalf.
+//
+// If c.Base is http.DefaultTransport, rhttp swaps in a freshly built
+// equivalent transport first. ConfigureTransports mutates the transport it's
+// given, and even *http.Transport.Clone lazily finalizes TLSNextProto on its
+// receiver as a side effect, so rhttp must not call either one directly on
+// the process-global shared by every other consumer of http.DefaultTransport.
+func configureHTTP2(c *Config) {
+	if !c.HTTP2.Enabled {
+		return
+	}
+	t, ok := c.Base.(*http.Transport)
+	if !ok {
+		return
+	}
+	if t == http.DefaultTransport {
+		t = newBaseTransport(BaseTransportConfig{})
+		c.Base = t
+	}
+
+	h2, err := http2.ConfigureTransports(t)
+	if err != nil {
+		return
+	}
+	h2.ReadIdleTimeout = c.HTTP2.readIdleTimeout()
+	h2.PingTimeout = c.HTTP2.pingTimeout()
+	h2.WriteByteTimeout = c.HTTP2.WriteByteTimeout
+	if c.HTTP2.MaxHeaderListSize > 0 {
+		h2.MaxHeaderListSize = c.HTTP2.MaxHeaderListSize
+	}
+}
@@ -0,0 +1,80 @@
+package rhttp
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestNewBaseTransport_AppliesDefaults(t *testing.T) {
+	tr := newBaseTransport(BaseTransportConfig{})
+
+	if tr.MaxIdleConns != 100 {
+		t.Fatalf("expected default MaxIdleConns 100, got %d", tr.MaxIdleConns)
+	}
+	if tr.MaxIdleConnsPerHost != 10 {
+		t.Fatalf("expected default MaxIdleConnsPerHost 10, got %d", tr.MaxIdleConnsPerHost)
+	}
+	if tr.IdleConnTimeout != 90*time.Second {
+		t.Fatalf("expected default IdleConnTimeout 90s, got %s", tr.IdleConnTimeout)
+	}
+	if tr.TLSHandshakeTimeout != 10*time.Second {
+		t.Fatalf("expected default TLSHandshakeTimeout 10s, got %s", tr.TLSHandshakeTimeout)
+	}
+	if tr.ExpectContinueTimeout != time.Second {
+		t.Fatalf("expected default ExpectContinueTimeout 1s, got %s", tr.ExpectContinueTimeout)
+	}
+	if !tr.ForceAttemptHTTP2 {
+		t.Fatalf("expected ForceAttemptHTTP2 to default to true")
+	}
+}
+
+func TestNewBaseTransport_HonorsOverrides(t *testing.T) {
+	tr := newBaseTransport(BaseTransportConfig{
+		MaxIdleConns:          5,
+		MaxIdleConnsPerHost:   2,
+		MaxConnsPerHost:       3,
+		IdleConnTimeout:       time.Minute,
+		ResponseHeaderTimeout: 2 * time.Second,
+		ForceAttemptHTTP2:     boolPtr(false),
+	})
+
+	if tr.MaxIdleConns != 5 || tr.MaxIdleConnsPerHost != 2 || tr.MaxConnsPerHost != 3 {
+		t.Fatalf("expected overrides to be honored, got %+v", tr)
+	}
+	if tr.IdleConnTimeout != time.Minute {
+		t.Fatalf("expected overridden IdleConnTimeout, got %s", tr.IdleConnTimeout)
+	}
+	if tr.ResponseHeaderTimeout != 2*time.Second {
+		t.Fatalf("expected overridden ResponseHeaderTimeout, got %s", tr.ResponseHeaderTimeout)
+	}
+	if tr.ForceAttemptHTTP2 {
+		t.Fatalf("expected ForceAttemptHTTP2: false to be honored")
+	}
+}
+
+func TestConfigureHTTP2_NoopWithoutStdlibTransport(t *testing.T) {
+	cfg := Config{Base: &scriptedRT{}, HTTP2: HTTP2Config{Enabled: true}}
+	configureHTTP2(&cfg) // must not panic when Base isn't *http.Transport
+
+	if _, ok := cfg.Base.(*scriptedRT); !ok {
+		t.Fatalf("expected Base to be left untouched")
+	}
+}
+
+func TestConfigureHTTP2_DoesNotMutateDefaultTransport(t *testing.T) {
+	before := http.DefaultTransport.(*http.Transport).TLSNextProto
+
+	cfg := Config{Base: http.DefaultTransport, HTTP2: HTTP2Config{Enabled: true}}
+	configureHTTP2(&cfg)
+
+	if cfg.Base == http.DefaultTransport {
+		t.Fatalf("expected configureHTTP2 to swap in an owned transport, not reuse http.DefaultTransport")
+	}
+	after := http.DefaultTransport.(*http.Transport).TLSNextProto
+	if len(after) != len(before) {
+		t.Fatalf("expected http.DefaultTransport to be left untouched, TLSNextProto changed from %d to %d entries", len(before), len(after))
+	}
+}
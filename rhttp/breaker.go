@@ -0,0 +1,250 @@
+package rhttp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrBreakerOpen is the sentinel a BreakerOpenError wraps, so callers (and
+// the retry layer) can test for it with errors.Is without caring which key
+// tripped.
+var ErrBreakerOpen = errors.New("rhttp: circuit breaker open")
+
+// BreakerOpenError is returned by the breaker transport when it short-
+// circuits a request because the breaker for req's key is open.
+type BreakerOpenError struct {
+	Key string
+}
+
+func (e *BreakerOpenError) Error() string {
+	return fmt.Sprintf("rhttp: circuit breaker open for %q", e.Key)
+}
+
+func (e *BreakerOpenError) Is(target error) bool { return target == ErrBreakerOpen }
+
+// DefaultTripPredicate classifies transport errors and 5xx responses as
+// breaker failures.
+func DefaultTripPredicate(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp != nil && resp.StatusCode >= 500
+}
+
+type breakerStatus int32
+
+const (
+	breakerClosed breakerStatus = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerStatus) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerState is a point-in-time snapshot of a breaker entry, for
+// observability.
+type BreakerState struct {
+	Key                 string
+	Status              string
+	ConsecutiveFailures int64
+	Requests            int64
+	Failures            int64
+	OpenedAt            time.Time
+}
+
+// breakerEntry tracks the state for a single breaker key. Counters reset when
+// the breaker enters or leaves half-open, so while closed they cover the
+// period since it last closed; a breaker that trips keeps the counts that
+// tripped it until it goes half-open.
+type breakerEntry struct {
+	mu sync.Mutex
+
+	status              breakerStatus
+	openedAt            time.Time
+	consecutiveFailures int64
+	requests            int64
+	failures            int64
+	halfOpenInflight    int64
+}
+
+// allow reports whether a request for this entry may proceed, handling the
+// open -> half-open timeout transition and capping half-open concurrency.
+func (e *breakerEntry) allow(cfg BreakerConfig) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch e.status {
+	case breakerClosed:
+		return true
+	case breakerOpen:
+		if time.Since(e.openedAt) < cfg.openTimeout() {
+			return false
+		}
+		e.status = breakerHalfOpen
+		e.halfOpenInflight = 0
+		e.consecutiveFailures = 0
+		e.requests = 0
+		e.failures = 0
+		fallthrough
+	case breakerHalfOpen:
+		max := cfg.HalfOpenMaxInflight
+		if max <= 0 {
+			max = 1
+		}
+		if e.halfOpenInflight >= int64(max) {
+			return false
+		}
+		e.halfOpenInflight++
+		return true
+	}
+	return true
+}
+
+// record updates counters with the outcome of a request that was allowed
+// through, tripping or resetting the breaker as needed.
+func (e *breakerEntry) record(cfg BreakerConfig, failed bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch e.status {
+	case breakerHalfOpen:
+		e.halfOpenInflight--
+		if failed {
+			e.status = breakerOpen
+			e.openedAt = time.Now()
+			e.consecutiveFailures, e.requests, e.failures = 0, 0, 0
+			return
+		}
+		e.status = breakerClosed
+		e.consecutiveFailures, e.requests, e.failures = 0, 0, 0
+		return
+	case breakerOpen:
+		// Lost a race with an allow() timeout transition; nothing to record.
+		return
+	}
+
+	e.requests++
+	if failed {
+		e.consecutiveFailures++
+		e.failures++
+	} else {
+		e.consecutiveFailures = 0
+	}
+
+	if e.tripped(cfg) {
+		e.status = breakerOpen
+		e.openedAt = time.Now()
+	}
+}
+
+func (e *breakerEntry) tripped(cfg BreakerConfig) bool {
+	threshold := cfg.FailureThreshold
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cfg.MinRequests > 0 && cfg.FailureThreshold > 0 {
+		if e.requests < int64(cfg.MinRequests) {
+			return false
+		}
+		return float64(e.failures)/float64(e.requests) >= threshold
+	}
+	// Consecutive-count mode has no denominator to apply a ratio against, so
+	// a fractional threshold (e.g. a ratio set without MinRequests) is
+	// rounded up rather than truncated to zero, which would trip the
+	// breaker on the very first request.
+	count := int64(math.Ceil(threshold))
+	if count < 1 {
+		count = 1
+	}
+	return e.consecutiveFailures >= count
+}
+
+func (cfg BreakerConfig) openTimeout() time.Duration {
+	if cfg.OpenTimeout > 0 {
+		return cfg.OpenTimeout
+	}
+	return 30 * time.Second
+}
+
+// BreakerTransport is the circuit breaker transport. It is exported so
+// callers can reach it via WithBreakerHandle and inspect per-host state
+// with Snapshot.
+type BreakerTransport struct {
+	next http.RoundTripper
+	cfg  BreakerConfig
+
+	mu      sync.Mutex
+	entries map[string]*breakerEntry
+}
+
+func newBreakerTransport(next http.RoundTripper, cfg BreakerConfig) *BreakerTransport {
+	return &BreakerTransport{next: next, cfg: cfg, entries: make(map[string]*breakerEntry)}
+}
+
+func (t *BreakerTransport) key(req *http.Request) string {
+	if t.cfg.KeyFunc != nil {
+		return t.cfg.KeyFunc(req)
+	}
+	if req.URL != nil && req.URL.Host != "" {
+		return req.URL.Host
+	}
+	return req.Host
+}
+
+func (t *BreakerTransport) entryFor(key string) *breakerEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e, ok := t.entries[key]
+	if !ok {
+		e = &breakerEntry{}
+		t.entries[key] = e
+	}
+	return e
+}
+
+func (t *BreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := t.key(req)
+	entry := t.entryFor(key)
+
+	if !entry.allow(t.cfg) {
+		return nil, &BreakerOpenError{Key: key}
+	}
+
+	tripOn := t.cfg.TripOn
+	if tripOn == nil {
+		tripOn = DefaultTripPredicate
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	entry.record(t.cfg, tripOn(resp, err))
+	return resp, err
+}
+
+// Snapshot returns the current breaker state for key, for observability.
+// An unknown key reports a closed breaker that has never seen a request.
+func (t *BreakerTransport) Snapshot(key string) BreakerState {
+	entry := t.entryFor(key)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	return BreakerState{
+		Key:                 key,
+		Status:              entry.status.String(),
+		ConsecutiveFailures: entry.consecutiveFailures,
+		Requests:            entry.requests,
+		Failures:            entry.failures,
+		OpenedAt:            entry.openedAt,
+	}
+}
@@ -0,0 +1,96 @@
+package rhttp
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBreakerTransport_FractionalThresholdWithoutMinRequestsRoundsUp(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 200}, {status: 500}}}
+
+	bt := newBreakerTransport(base, BreakerConfig{FailureThreshold: 0.5})
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	resp, err := bt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error on first (successful) request: %v", err)
+	}
+	resp.Body.Close()
+
+	if snap := bt.Snapshot("example.com"); snap.Status != "closed" {
+		t.Fatalf("expected a fractional threshold to round up rather than trip on the first request, got %s", snap.Status)
+	}
+}
+
+func TestBreakerTransport_OpensAfterConsecutiveFailures(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 500}, {status: 500}, {status: 200}}}
+
+	bt := newBreakerTransport(base, BreakerConfig{FailureThreshold: 2})
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	for i := 0; i < 2; i++ {
+		resp, err := bt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("attempt %d: unexpected error: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+
+	_, err := bt.RoundTrip(req)
+	var openErr *BreakerOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("expected *BreakerOpenError once tripped, got %v", err)
+	}
+	if !errors.Is(err, ErrBreakerOpen) {
+		t.Fatalf("expected errors.Is(err, ErrBreakerOpen) to hold")
+	}
+	if base.calls != 2 {
+		t.Fatalf("expected base to not be called while open, got %d calls", base.calls)
+	}
+}
+
+func TestBreakerTransport_HalfOpenProbeCloses(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 500}, {status: 200}}}
+
+	bt := newBreakerTransport(base, BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+	})
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	resp, _ := bt.RoundTrip(req)
+	resp.Body.Close()
+
+	if snap := bt.Snapshot("example.com"); snap.Status != "open" {
+		t.Fatalf("expected breaker to be open, got %s", snap.Status)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	resp, err := bt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("expected half-open probe to reach base, got %v", err)
+	}
+	resp.Body.Close()
+
+	if snap := bt.Snapshot("example.com"); snap.Status != "closed" {
+		t.Fatalf("expected breaker to close after a successful probe, got %s", snap.Status)
+	}
+}
+
+func TestBreakerTransport_KeysPerHost(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 500}, {status: 500}, {status: 200}}}
+
+	bt := newBreakerTransport(base, BreakerConfig{FailureThreshold: 1})
+
+	failing, _ := http.NewRequest(http.MethodGet, "http://a.example.com", nil)
+	resp, _ := bt.RoundTrip(failing)
+	resp.Body.Close()
+
+	other, _ := http.NewRequest(http.MethodGet, "http://b.example.com", nil)
+	if _, err := bt.RoundTrip(other); err != nil {
+		t.Fatalf("unrelated host should not be short-circuited: %v", err)
+	}
+}
@@ -1,28 +1,122 @@
 package rhttp
 
-import "net/http"
+import (
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
 
 // Option configures rhttp behavior.
 type Option func(*Config)
 
 // Config is the internal configuration built from defaults + options.
 type Config struct {
-	Base    http.RoundTripper
-	Retry   RetryConfig
-	Breaker BreakerConfig
-	OTel    OTelConfig
+	Base             http.RoundTripper
+	Retry            RetryConfig
+	Breaker          BreakerConfig
+	OTel             OTelConfig
+	RequestModifiers []RequestModifier
+	HTTP2            HTTP2Config
+	Hedging          HedgingConfig
+
+	// breakerHandle, if set via WithBreakerHandle, receives the constructed
+	// *BreakerTransport once buildTransport assembles the chain.
+	breakerHandle **BreakerTransport
 }
 
+// RetryConfig configures the retry transport.
 type RetryConfig struct {
 	Enabled bool
+
+	// MaxAttempts is the maximum number of attempts, including the first.
+	// Defaults to 3.
+	MaxAttempts int
+
+	// InitialInterval is the base delay before the first retry. Defaults to
+	// 500ms.
+	InitialInterval time.Duration
+	// MaxInterval caps the computed backoff delay. Zero means no cap.
+	MaxInterval time.Duration
+	// Multiplier scales InitialInterval after each attempt. Defaults to 2.
+	Multiplier float64
+	// Jitter randomizes the computed delay within [1-Jitter, 1+Jitter].
+	Jitter float64
+	// MaxElapsedTime bounds the total time spent retrying, across all
+	// attempts. Zero means no limit.
+	MaxElapsedTime time.Duration
+
+	// Predicate decides whether a response/error should be retried.
+	// Defaults to DefaultRetryPredicate.
+	Predicate func(*http.Response, error) bool
+	// Backoff computes the delay before a given attempt. Defaults to
+	// ExponentialBackoff.
+	Backoff func(attempt int, cfg RetryConfig) time.Duration
 }
 
+// BreakerConfig configures the circuit breaker transport.
 type BreakerConfig struct {
 	Enabled bool
+
+	// FailureThreshold is either a consecutive-failure count (when
+	// MinRequests is zero) or a failure ratio in (0,1] evaluated once at
+	// least MinRequests requests have been observed. Defaults to 5
+	// consecutive failures. A fractional value given without MinRequests is
+	// rounded up, since there is no ratio denominator to apply it against.
+	FailureThreshold float64
+	// MinRequests is the minimum number of requests observed before
+	// FailureThreshold is evaluated as a ratio instead of a consecutive
+	// count.
+	MinRequests int
+	// OpenTimeout is how long the breaker stays open before allowing a
+	// half-open probe. Defaults to 30s.
+	OpenTimeout time.Duration
+	// HalfOpenMaxInflight caps the number of concurrent probe requests
+	// allowed while half-open. Defaults to 1.
+	HalfOpenMaxInflight int
+
+	// TripOn classifies a response/error as a breaker failure. Defaults to
+	// DefaultTripPredicate.
+	TripOn func(*http.Response, error) bool
+	// KeyFunc derives the breaker key for a request. Defaults to the
+	// request host.
+	KeyFunc func(*http.Request) string
 }
 
+// OTelConfig configures the OpenTelemetry instrumentation transport.
 type OTelConfig struct {
 	Enabled bool
+
+	// TracerProvider supplies the Tracer used to start client spans.
+	// Defaults to otel.GetTracerProvider().
+	TracerProvider trace.TracerProvider
+	// MeterProvider supplies the Meter used to record metrics. Defaults to
+	// otel.GetMeterProvider().
+	MeterProvider metric.MeterProvider
+	// Propagator injects the trace context into outgoing requests. Defaults
+	// to a composite of the W3C tracecontext and baggage propagators.
+	Propagator propagation.TextMapPropagator
+	// SpanNameFormatter names the client span for a request. Defaults to
+	// the request method.
+	SpanNameFormatter func(*http.Request) string
+}
+
+// HedgingConfig configures the hedged-request transport.
+type HedgingConfig struct {
+	Enabled bool
+
+	// Delay is how long to wait for a response before firing an additional
+	// in-flight attempt. Defaults to 50ms.
+	Delay time.Duration
+	// MaxAttempts caps the number of concurrent attempts, including the
+	// first. Defaults to 2.
+	MaxAttempts int
+
+	// Predicate decides whether a response/error is good enough to win the
+	// race. Defaults to DefaultHedgePredicate.
+	Predicate func(*http.Response, error) bool
 }
 
 func defaultConfig(base http.RoundTripper) Config {
@@ -62,6 +156,27 @@ func WithBreaker(cfg BreakerConfig) Option {
 	return func(c *Config) { c.Breaker = cfg }
 }
 
+// WithBreakerHandle arranges for *handle to be set to the constructed
+// *BreakerTransport once NewTransport/NewClient assembles the chain, so
+// callers can read per-host breaker state via BreakerTransport.Snapshot.
+// Use alongside WithBreaker; a nil handle is a no-op.
+func WithBreakerHandle(handle **BreakerTransport) Option {
+	return func(c *Config) { c.breakerHandle = handle }
+}
+
 func WithOTel(cfg OTelConfig) Option {
 	return func(c *Config) { c.OTel = cfg }
 }
+
+// WithRequestModifiers appends modifiers to the request-modifier chain.
+// Each modifier runs, in order, against a fresh clone of every outgoing
+// request, including each retry attempt.
+func WithRequestModifiers(modifiers ...RequestModifier) Option {
+	return func(c *Config) { c.RequestModifiers = append(c.RequestModifiers, modifiers...) }
+}
+
+// WithHedging enables hedged requests: additional in-flight attempts fired
+// after cfg.Delay elapses without a response, racing the first to win.
+func WithHedging(cfg HedgingConfig) Option {
+	return func(c *Config) { c.Hedging = cfg }
+}
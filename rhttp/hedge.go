@@ -0,0 +1,133 @@
+package rhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// DefaultHedgePredicate treats a response as a winner when it completed
+// without a transport error and did not return a 5xx status.
+func DefaultHedgePredicate(resp *http.Response, err error) bool {
+	if err != nil {
+		return false
+	}
+	return resp != nil && resp.StatusCode < 500
+}
+
+type hedgeResult struct {
+	resp *http.Response
+	err  error
+}
+
+// hedgeTransport fires additional in-flight attempts for a request that is
+// taking too long, racing them and returning whichever succeeds first. It
+// complements retry (which only fires after a failure) with a tail-latency
+// mitigation strategy.
+type hedgeTransport struct {
+	next http.RoundTripper
+	cfg  HedgingConfig
+}
+
+func (t *hedgeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	maxAttempts := t.cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 2
+	}
+	delay := t.cfg.Delay
+	if delay <= 0 {
+		delay = 50 * time.Millisecond
+	}
+	predicate := t.cfg.Predicate
+	if predicate == nil {
+		predicate = DefaultHedgePredicate
+	}
+
+	getBody, replayable := prepareReplay(req)
+	if !replayable || maxAttempts <= 1 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+
+	results := make(chan hedgeResult, maxAttempts)
+	launch := func() {
+		body, err := getBody()
+		if err != nil {
+			results <- hedgeResult{err: err}
+			return
+		}
+		clone := req.Clone(ctx)
+		clone.Body = body
+		resp, err := t.next.RoundTrip(clone)
+		results <- hedgeResult{resp: resp, err: err}
+	}
+
+	go launch()
+	pending := 1
+	remaining := maxAttempts - 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var fallback *hedgeResult
+	for {
+		var timerCh <-chan time.Time
+		if remaining > 0 {
+			timerCh = timer.C
+		}
+
+		select {
+		case res := <-results:
+			pending--
+
+			// A tripped breaker means rhttp should shed load, not pile on
+			// more hedge attempts against the same host.
+			if errors.Is(res.err, ErrBreakerOpen) {
+				cancel()
+				go drainHedgeResults(results, pending)
+				return res.resp, res.err
+			}
+
+			if predicate(res.resp, res.err) {
+				cancel()
+				go drainHedgeResults(results, pending)
+				if fallback != nil && fallback.resp != nil {
+					drainAndClose(fallback.resp)
+				}
+				return res.resp, res.err
+			}
+
+			if fallback != nil && fallback.resp != nil {
+				drainAndClose(fallback.resp)
+			}
+			fallback = &res
+
+			if pending == 0 && remaining == 0 {
+				cancel()
+				return fallback.resp, fallback.err
+			}
+		case <-timerCh:
+			remaining--
+			pending++
+			go launch()
+			if remaining > 0 {
+				timer.Reset(delay)
+			}
+		}
+	}
+}
+
+// drainHedgeResults discards the bodies of n in-flight hedge attempts that
+// lost the race, once their results arrive, so their connections can be
+// returned to the base transport's pool.
+func drainHedgeResults(results <-chan hedgeResult, n int) {
+	for i := 0; i < n; i++ {
+		res := <-results
+		if res.resp != nil {
+			drainAndClose(res.resp)
+		}
+	}
+}
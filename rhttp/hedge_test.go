@@ -0,0 +1,139 @@
+package rhttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// hedgeStepRT answers every RoundTrip call after the same configured delay,
+// so tests can control whether the first attempt answers before the hedge
+// delay elapses. It is safe for concurrent use, unlike scriptedRT.
+type hedgeStepRT struct {
+	delay  time.Duration
+	status int
+	err    error
+	calls  int32
+}
+
+func (rt *hedgeStepRT) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&rt.calls, 1)
+	if rt.delay > 0 {
+		select {
+		case <-time.After(rt.delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	if rt.err != nil {
+		return nil, rt.err
+	}
+	return &http.Response{
+		StatusCode: rt.status,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func TestHedgeTransport_FirstAttemptWinsBeforeDelay(t *testing.T) {
+	base := &hedgeStepRT{status: 200}
+
+	ht := &hedgeTransport{next: base, cfg: HedgingConfig{Delay: 50 * time.Millisecond}}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	resp, err := ht.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if calls := atomic.LoadInt32(&base.calls); calls != 1 {
+		t.Fatalf("expected only 1 attempt, got %d", calls)
+	}
+}
+
+// firstSlowRT stalls its first call for a long time and answers every
+// subsequent call immediately, so a test can tell whether the hedged
+// attempt (rather than the original) won the race.
+type firstSlowRT struct {
+	slowFor time.Duration
+	status  int
+	calls   int32
+}
+
+func (rt *firstSlowRT) RoundTrip(req *http.Request) (*http.Response, error) {
+	if atomic.AddInt32(&rt.calls, 1) == 1 {
+		select {
+		case <-time.After(rt.slowFor):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return &http.Response{
+		StatusCode: rt.status,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func TestHedgeTransport_SlowFirstAttemptIsHedged(t *testing.T) {
+	base := &firstSlowRT{slowFor: 200 * time.Millisecond, status: 200}
+
+	ht := &hedgeTransport{next: base, cfg: HedgingConfig{Delay: 10 * time.Millisecond}}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	start := time.Now()
+	resp, err := ht.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Fatalf("expected hedge attempt to win well before the slow attempt, took %s", elapsed)
+	}
+	if calls := atomic.LoadInt32(&base.calls); calls != 2 {
+		t.Fatalf("expected both the original and hedged attempt to fire, got %d", calls)
+	}
+}
+
+func TestHedgeTransport_BreakerOpenShortCircuits(t *testing.T) {
+	base := &hedgeStepRT{err: &BreakerOpenError{Key: "example.com"}}
+
+	ht := &hedgeTransport{next: base, cfg: HedgingConfig{Delay: time.Hour}}
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	_, err := ht.RoundTrip(req)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if be, ok := err.(*BreakerOpenError); !ok || be.Key != "example.com" {
+		t.Fatalf("expected the breaker-open error to surface unchanged, got %v", err)
+	}
+	if calls := atomic.LoadInt32(&base.calls); calls != 1 {
+		t.Fatalf("expected no hedge attempt once the breaker reports open, got %d", calls)
+	}
+}
+
+func TestHedgeTransport_NonReplayableRequestMakesSingleAttempt(t *testing.T) {
+	base := &hedgeStepRT{status: 200}
+
+	ht := &hedgeTransport{next: base, cfg: HedgingConfig{Delay: time.Millisecond}}
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", io.NopCloser(bytes.NewBufferString("payload")))
+
+	resp, err := ht.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	time.Sleep(10 * time.Millisecond)
+	if calls := atomic.LoadInt32(&base.calls); calls != 1 {
+		t.Fatalf("expected exactly 1 attempt for a non-replayable request, got %d", calls)
+	}
+}
@@ -0,0 +1,129 @@
+package rhttp
+
+import (
+	"context"
+	"net/http"
+)
+
+// RequestModifier mutates an outgoing request before it is sent. Modifiers
+// run against a clone of the caller's request, so mutating it (headers,
+// context, etc.) never affects the caller's original request or other
+// in-flight retry attempts.
+type RequestModifier interface {
+	ModifyRequest(*http.Request) error
+}
+
+// RequestModifierFunc adapts a plain function to a RequestModifier.
+type RequestModifierFunc func(*http.Request) error
+
+func (f RequestModifierFunc) ModifyRequest(req *http.Request) error { return f(req) }
+
+// HeaderModifier sets (overwriting any existing values) the given headers
+// on every outgoing request.
+type HeaderModifier struct {
+	Header http.Header
+}
+
+func (m HeaderModifier) ModifyRequest(req *http.Request) error {
+	for k, vs := range m.Header {
+		req.Header.Del(k)
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	return nil
+}
+
+// BearerAuthModifier stamps an Authorization: Bearer header onto every
+// outgoing request.
+type BearerAuthModifier struct {
+	Token string
+}
+
+func (m BearerAuthModifier) ModifyRequest(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+m.Token)
+	return nil
+}
+
+// BasicAuthModifier stamps HTTP Basic credentials onto every outgoing
+// request.
+type BasicAuthModifier struct {
+	Username string
+	Password string
+}
+
+func (m BasicAuthModifier) ModifyRequest(req *http.Request) error {
+	req.SetBasicAuth(m.Username, m.Password)
+	return nil
+}
+
+// UserAgentModifier overwrites the User-Agent header on every outgoing
+// request.
+type UserAgentModifier struct {
+	UserAgent string
+}
+
+func (m UserAgentModifier) ModifyRequest(req *http.Request) error {
+	req.Header.Set("User-Agent", m.UserAgent)
+	return nil
+}
+
+type contextTagsKey struct{}
+
+// ContextTags returns the tags attached to ctx by a ContextTagModifier, if
+// any. Useful for downstream layers (e.g. OTel) that want to surface
+// caller-supplied metadata.
+func ContextTags(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(contextTagsKey{}).(map[string]string)
+	return tags
+}
+
+// ContextTagModifier attaches fixed key/value tags to the request's context,
+// merging with any tags already present.
+type ContextTagModifier struct {
+	Tags map[string]string
+}
+
+func (m ContextTagModifier) ModifyRequest(req *http.Request) error {
+	merged := make(map[string]string, len(m.Tags))
+	for k, v := range ContextTags(req.Context()) {
+		merged[k] = v
+	}
+	for k, v := range m.Tags {
+		merged[k] = v
+	}
+	*req = *req.WithContext(context.WithValue(req.Context(), contextTagsKey{}, merged))
+	return nil
+}
+
+// modifierTransport clones each request and runs it through the configured
+// modifiers before handing it to next, so each retry attempt gets its own
+// freshly modified clone. Hedged attempts are made beneath this layer, from
+// the one clone it modified.
+type modifierTransport struct {
+	next      http.RoundTripper
+	modifiers []RequestModifier
+}
+
+func (t *modifierTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.modifiers) == 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+
+	for _, m := range t.modifiers {
+		if err := m.ModifyRequest(clone); err != nil {
+			return nil, err
+		}
+	}
+
+	return t.next.RoundTrip(clone)
+}
@@ -0,0 +1,86 @@
+package rhttp
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+func TestModifierTransport_ClonesRequest(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 200}}}
+	mt := &modifierTransport{next: base, modifiers: []RequestModifier{
+		HeaderModifier{Header: http.Header{"X-Test": []string{"1"}}},
+	}}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := mt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if req.Header.Get("X-Test") != "" {
+		t.Fatalf("expected original request to be untouched, got header %q", req.Header.Get("X-Test"))
+	}
+	if base.last.Header.Get("X-Test") != "1" {
+		t.Fatalf("expected downstream request to carry the injected header")
+	}
+}
+
+func TestModifierTransport_RunsInOrder(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 200}}}
+	mt := &modifierTransport{next: base, modifiers: []RequestModifier{
+		BearerAuthModifier{Token: "abc"},
+		UserAgentModifier{UserAgent: "rhttp-test/1.0"},
+	}}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := mt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := base.last.Header.Get("Authorization"); got != "Bearer abc" {
+		t.Fatalf("expected bearer auth header, got %q", got)
+	}
+	if got := base.last.Header.Get("User-Agent"); got != "rhttp-test/1.0" {
+		t.Fatalf("expected stamped user agent, got %q", got)
+	}
+}
+
+func TestModifierTransport_ReplaysBodyOnClone(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 200}}}
+	mt := &modifierTransport{next: base, modifiers: []RequestModifier{
+		HeaderModifier{Header: http.Header{"X-Test": []string{"1"}}},
+	}}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", bytes.NewBufferString("payload"))
+	resp, err := mt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(base.bodies) != 1 || base.bodies[0] != "payload" {
+		t.Fatalf("expected downstream body %q, got %v", "payload", base.bodies)
+	}
+}
+
+func TestContextTagModifier_MergesTags(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+	m1 := ContextTagModifier{Tags: map[string]string{"a": "1"}}
+	if err := m1.ModifyRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m2 := ContextTagModifier{Tags: map[string]string{"b": "2"}}
+	if err := m2.ModifyRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tags := ContextTags(req.Context())
+	if tags["a"] != "1" || tags["b"] != "2" {
+		t.Fatalf("expected merged tags, got %v", tags)
+	}
+}
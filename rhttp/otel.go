@@ -0,0 +1,176 @@
+package rhttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/AlexPetraglia/rhttp"
+
+type retryHookKey struct{}
+
+// retryHook lets the retry transport notify an outer layer (otel) of each
+// retry attempt without otel.go's dependencies leaking into retry.go.
+type retryHook func(attempt int, resp *http.Response, err error)
+
+func withRetryHook(ctx context.Context, hook retryHook) context.Context {
+	return context.WithValue(ctx, retryHookKey{}, hook)
+}
+
+// notifyRetry invokes the retry hook attached to ctx, if any.
+func notifyRetry(ctx context.Context, attempt int, resp *http.Response, err error) {
+	if hook, ok := ctx.Value(retryHookKey{}).(retryHook); ok && hook != nil {
+		hook(attempt, resp, err)
+	}
+}
+
+// otelTransport instruments RoundTrip with an OpenTelemetry client span and
+// duration/in-flight/retry metrics. Since it sits outermost in the chain, a
+// span's duration covers every retry attempt made underneath it.
+type otelTransport struct {
+	next http.RoundTripper
+	cfg  OTelConfig
+
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	nameFn     func(*http.Request) string
+
+	duration metric.Float64Histogram
+	inflight metric.Int64UpDownCounter
+	retries  metric.Int64Counter
+}
+
+func newOTelTransport(next http.RoundTripper, cfg OTelConfig) *otelTransport {
+	tp := cfg.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	mp := cfg.MeterProvider
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	prop := cfg.Propagator
+	if prop == nil {
+		prop = propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		)
+	}
+	nameFn := cfg.SpanNameFormatter
+	if nameFn == nil {
+		nameFn = func(req *http.Request) string { return req.Method }
+	}
+
+	meter := mp.Meter(instrumentationName)
+	duration, _ := meter.Float64Histogram(
+		"http.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of outgoing HTTP requests, including retries."),
+	)
+	inflight, _ := meter.Int64UpDownCounter(
+		"http.client.request.inflight",
+		metric.WithDescription("Number of in-flight outgoing HTTP requests."),
+	)
+	retries, _ := meter.Int64Counter(
+		"http.client.request.retries",
+		metric.WithDescription("Number of retry attempts made by the retry transport."),
+	)
+
+	return &otelTransport{
+		next:       next,
+		cfg:        cfg,
+		tracer:     tp.Tracer(instrumentationName),
+		propagator: prop,
+		nameFn:     nameFn,
+		duration:   duration,
+		inflight:   inflight,
+		retries:    retries,
+	}
+}
+
+func (t *otelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.URLFull(req.URL.String()),
+	}
+	if host := req.URL.Hostname(); host != "" {
+		attrs = append(attrs, semconv.ServerAddress(host))
+	}
+	if port := req.URL.Port(); port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			attrs = append(attrs, semconv.ServerPort(p))
+		}
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), t.nameFn(req),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	t.inflight.Add(ctx, 1)
+	defer t.inflight.Add(ctx, -1)
+
+	var retryCount int
+	ctx = withRetryHook(ctx, func(attempt int, resp *http.Response, err error) {
+		retryCount++
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("rhttp.retry.attempt", attempt),
+		))
+	})
+
+	// Clone rather than req.WithContext, since WithContext is a shallow copy
+	// that shares the caller's Header map; injecting trace context into it
+	// would mutate a request rhttp doesn't own.
+	req = req.Clone(ctx)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+
+	t.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	if retryCount > 0 {
+		t.retries.Add(ctx, int64(retryCount), metric.WithAttributes(attrs...))
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if proto := protocolVersion(resp); proto != "" {
+		span.SetAttributes(semconv.NetworkProtocolVersion(proto))
+	}
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, "")
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+
+	return resp, nil
+}
+
+func protocolVersion(resp *http.Response) string {
+	switch resp.ProtoMajor {
+	case 2:
+		return "2"
+	case 1:
+		return fmt.Sprintf("1.%d", resp.ProtoMinor)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,117 @@
+package rhttp
+
+import (
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestOTelTransport_DelegatesToNext(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 200}}}
+	ot := newOTelTransport(base, OTelConfig{})
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com:8443/path", nil)
+	resp, err := ot.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if base.calls != 1 {
+		t.Fatalf("expected base to be called once, got %d", base.calls)
+	}
+}
+
+func TestOTelTransport_AttachesRetryHookToContext(t *testing.T) {
+	hookSeen := false
+	base := &scriptedRT{responses: []scriptedResp{{status: 200}}}
+	probe := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if _, ok := req.Context().Value(retryHookKey{}).(retryHook); ok {
+			hookSeen = true
+		}
+		return base.RoundTrip(req)
+	})
+
+	ot := newOTelTransport(probe, OTelConfig{})
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := ot.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if !hookSeen {
+		t.Fatalf("expected the retry hook to be attached to the request context")
+	}
+}
+
+func TestOTelTransport_CustomSpanNameFormatter(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 200}}}
+
+	var gotName string
+	ot := newOTelTransport(base, OTelConfig{
+		SpanNameFormatter: func(req *http.Request) string {
+			gotName = req.Method + " " + req.URL.Path
+			return gotName
+		},
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/widgets", nil)
+	resp, err := ot.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if gotName != "GET /widgets" {
+		t.Fatalf("expected span name formatter to be invoked, got %q", gotName)
+	}
+}
+
+func TestOTelTransport_4xxResponseSetsErrorStatus(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	base := &scriptedRT{responses: []scriptedResp{{status: 404}}}
+	ot := newOTelTransport(base, OTelConfig{TracerProvider: tp})
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com/missing", nil)
+	resp, err := ot.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 ended span, got %d", len(spans))
+	}
+	if got := spans[0].Status().Code; got != codes.Error {
+		t.Fatalf("expected a 404 response to set span status Error, got %v", got)
+	}
+}
+
+func TestOTelTransport_DoesNotMutateCallerRequest(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 200}}}
+	// A recording provider yields a valid span context, so trace context is
+	// actually injected into the outgoing request's headers.
+	ot := newOTelTransport(base, OTelConfig{TracerProvider: sdktrace.NewTracerProvider()})
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := ot.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, ok := req.Header["Traceparent"]; ok {
+		t.Fatalf("expected the caller's request headers to be left untouched, got %v", req.Header)
+	}
+}
@@ -0,0 +1,214 @@
+package rhttp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// defaultSafeMethods lists the methods considered safe to replay without a
+// caller-supplied Request.GetBody, because they are conventionally
+// idempotent.
+var defaultSafeMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+// RetryError is returned when the retry transport gives up on a transport
+// error, after MaxAttempts or once MaxElapsedTime would be exceeded (never on
+// a bad-but-well-formed response, which is instead returned as-is so callers
+// can inspect its status directly). Attempts is the number of attempts made.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("rhttp: giving up after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// DefaultRetryPredicate retries on transport errors and on 5xx responses
+// (except 501 Not Implemented, which indicates the server will never
+// support the request) and on 429 Too Many Requests. It never retries a
+// request short-circuited by an open circuit breaker, since that would
+// defeat the breaker's purpose of shedding load.
+func DefaultRetryPredicate(resp *http.Response, err error) bool {
+	if errors.Is(err, ErrBreakerOpen) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	if resp == nil {
+		return false
+	}
+	if resp.StatusCode == http.StatusNotImplemented {
+		return false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode >= 500
+}
+
+// ExponentialBackoff computes the delay before the given attempt (1-indexed:
+// the delay before the second attempt is ExponentialBackoff(1, cfg)) using
+// cfg.InitialInterval, cfg.Multiplier and cfg.MaxInterval, randomized by
+// cfg.Jitter.
+func ExponentialBackoff(attempt int, cfg RetryConfig) time.Duration {
+	initial := cfg.InitialInterval
+	if initial <= 0 {
+		initial = 500 * time.Millisecond
+	}
+	mult := cfg.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+
+	d := float64(initial) * math.Pow(mult, float64(attempt-1))
+	if cfg.MaxInterval > 0 && d > float64(cfg.MaxInterval) {
+		d = float64(cfg.MaxInterval)
+	}
+
+	if cfg.Jitter > 0 {
+		lo := 1 - cfg.Jitter
+		hi := 1 + cfg.Jitter
+		if lo < 0 {
+			lo = 0
+		}
+		d *= lo + rand.Float64()*(hi-lo)
+	}
+
+	return time.Duration(d)
+}
+
+// canReplay reports whether req may be retried at all: either its method is
+// conventionally idempotent, or the caller has supplied GetBody so rhttp
+// doesn't have to guess.
+func canReplay(req *http.Request) bool {
+	if defaultSafeMethods[req.Method] {
+		return true
+	}
+	return req.GetBody != nil
+}
+
+// prepareReplay returns a function producing a fresh copy of req's body for
+// each attempt, buffering it once if necessary, and reports whether req may
+// be retried at all.
+func prepareReplay(req *http.Request) (func() (io.ReadCloser, error), bool) {
+	if !canReplay(req) {
+		return nil, false
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return func() (io.ReadCloser, error) { return http.NoBody, nil }, true
+	}
+	if req.GetBody != nil {
+		return req.GetBody, true
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, false
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	getBody := func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.GetBody = getBody
+	return getBody, true
+}
+
+type retryTransport struct {
+	next http.RoundTripper
+	cfg  RetryConfig
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	predicate := t.cfg.Predicate
+	if predicate == nil {
+		predicate = DefaultRetryPredicate
+	}
+	backoff := t.cfg.Backoff
+	if backoff == nil {
+		backoff = ExponentialBackoff
+	}
+	maxAttempts := t.cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	getBody, replayable := prepareReplay(req)
+	if !replayable {
+		return t.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	var lastResp *http.Response
+	var lastErr error
+	var attempts int
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		attemptReq := req
+		if attempt > 1 {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			clone := req.Clone(req.Context())
+			clone.Body = body
+			attemptReq = clone
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		attempts = attempt
+		lastResp, lastErr = resp, err
+
+		if !predicate(resp, err) {
+			return resp, err
+		}
+		if attempt == maxAttempts {
+			break
+		}
+
+		notifyRetry(req.Context(), attempt, resp, err)
+
+		delay := backoff(attempt, t.cfg)
+		if resp != nil {
+			if ra, ok := retryAfterDelay(resp); ok {
+				delay = ra
+			}
+		}
+
+		if t.cfg.MaxElapsedTime > 0 && time.Since(start)+delay > t.cfg.MaxElapsedTime {
+			break
+		}
+
+		if resp != nil {
+			drainAndClose(resp)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	if lastErr != nil {
+		return nil, &RetryError{Attempts: attempts, Err: lastErr}
+	}
+	return lastResp, nil
+}
@@ -0,0 +1,188 @@
+package rhttp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport_RetriesOn5xxThenSucceeds(t *testing.T) {
+	base := &scriptedRT{
+		responses: []scriptedResp{
+			{status: 500},
+			{status: 200},
+		},
+	}
+
+	tr := &retryTransport{next: base, cfg: RetryConfig{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+	}}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		t.Fatalf("expected final status 200, got %d", resp.StatusCode)
+	}
+	if base.calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", base.calls)
+	}
+}
+
+func TestRetryTransport_GivesUpAfterMaxAttempts(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 500}, {status: 500}, {status: 500}}}
+
+	tr := &retryTransport{next: base, cfg: RetryConfig{
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+	}}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 500 {
+		t.Fatalf("expected last status 500, got %d", resp.StatusCode)
+	}
+	if base.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", base.calls)
+	}
+}
+
+func TestRetryTransport_MaxElapsedTimeLeavesResponseBodyReadable(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 503, body: "unavailable"}}}
+
+	tr := &retryTransport{next: base, cfg: RetryConfig{
+		MaxAttempts:     3,
+		InitialInterval: time.Hour,
+		MaxElapsedTime:  time.Millisecond,
+	}}
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 503 {
+		t.Fatalf("expected status 503, got %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	if string(data) != "unavailable" {
+		t.Fatalf("expected response body to still be readable, got %q", string(data))
+	}
+}
+
+func TestRetryTransport_UnsafeMethodWithoutGetBodyIsNotRetried(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 500}, {status: 200}}}
+
+	tr := &retryTransport{next: base, cfg: RetryConfig{MaxAttempts: 3}}
+
+	req, _ := http.NewRequest(http.MethodPost, "http://example.com", io.NopCloser(bytes.NewBufferString("payload")))
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 500 {
+		t.Fatalf("expected single unretried attempt to return 500, got %d", resp.StatusCode)
+	}
+	if base.calls != 1 {
+		t.Fatalf("expected 1 attempt, got %d", base.calls)
+	}
+}
+
+func TestRetryTransport_ReplaysBodyOnRetry(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 500}, {status: 200}}}
+
+	tr := &retryTransport{next: base, cfg: RetryConfig{
+		MaxAttempts:     2,
+		InitialInterval: time.Millisecond,
+	}}
+
+	req, _ := http.NewRequest(http.MethodPut, "http://example.com", bytes.NewBufferString("payload"))
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if base.calls != 2 {
+		t.Fatalf("expected 2 attempts, got %d", base.calls)
+	}
+	for i, body := range base.bodies {
+		if body != "payload" {
+			t.Fatalf("attempt %d: expected body %q, got %q", i+1, "payload", body)
+		}
+	}
+}
+
+func TestRetryTransport_AbortsOnContextCancellation(t *testing.T) {
+	base := &scriptedRT{responses: []scriptedResp{{status: 500}, {status: 500}}}
+
+	tr := &retryTransport{next: base, cfg: RetryConfig{
+		MaxAttempts:     5,
+		InitialInterval: time.Hour,
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	req = req.WithContext(ctx)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := tr.RoundTrip(req)
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+type scriptedResp struct {
+	status int
+	body   string
+}
+
+type scriptedRT struct {
+	responses []scriptedResp
+	calls     int
+	bodies    []string
+	last      *http.Request
+}
+
+func (rt *scriptedRT) RoundTrip(req *http.Request) (*http.Response, error) {
+	idx := rt.calls
+	rt.calls++
+	rt.last = req
+
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		rt.bodies = append(rt.bodies, string(data))
+	}
+
+	r := rt.responses[idx]
+	return &http.Response{
+		StatusCode: r.status,
+		Body:       io.NopCloser(bytes.NewBufferString(r.body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
@@ -3,7 +3,8 @@ package rhttp
 import "net/http"
 
 // NewTransport builds an http.RoundTripper with rhttp middleware.
-// Chain order (outer → inner): OTel → Retry → Breaker → Base.
+// Chain order (outer → inner): OTel → Retry → RequestModifiers → Hedging →
+// Breaker → Base.
 //
 // Features are enabled/disabled by config only.
 func NewTransport(base http.RoundTripper, opts ...Option) http.RoundTripper {
@@ -13,6 +14,7 @@ func NewTransport(base http.RoundTripper, opts ...Option) http.RoundTripper {
 			opt(&cfg)
 		}
 	}
+	configureHTTP2(&cfg)
 	return buildTransport(cfg)
 }
 
@@ -25,6 +27,7 @@ func NewClient(opts ...Option) *http.Client {
 			opt(&cfg)
 		}
 	}
+	configureHTTP2(&cfg)
 	return &http.Client{Transport: buildTransport(cfg)}
 }
 
@@ -33,42 +36,23 @@ func buildTransport(cfg Config) http.RoundTripper {
 
 	// Inner → outer build (so final order is outer → inner as documented).
 	if cfg.Breaker.Enabled {
-		rt = &breakerTransport{next: rt, cfg: cfg.Breaker}
+		bt := newBreakerTransport(rt, cfg.Breaker)
+		if cfg.breakerHandle != nil {
+			*cfg.breakerHandle = bt
+		}
+		rt = bt
+	}
+	if cfg.Hedging.Enabled {
+		rt = &hedgeTransport{next: rt, cfg: cfg.Hedging}
+	}
+	if len(cfg.RequestModifiers) > 0 {
+		rt = &modifierTransport{next: rt, modifiers: cfg.RequestModifiers}
 	}
 	if cfg.Retry.Enabled {
 		rt = &retryTransport{next: rt, cfg: cfg.Retry}
 	}
 	if cfg.OTel.Enabled {
-		rt = &otelTransport{next: rt, cfg: cfg.OTel}
+		rt = newOTelTransport(rt, cfg.OTel)
 	}
 	return rt
 }
-
-// --- Stub transports (real logic lands in v0.2+ / v0.3+ / v0.4+) ---
-
-type retryTransport struct {
-	next http.RoundTripper
-	cfg  RetryConfig
-}
-
-func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	return t.next.RoundTrip(req)
-}
-
-type breakerTransport struct {
-	next http.RoundTripper
-	cfg  BreakerConfig
-}
-
-func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	return t.next.RoundTrip(req)
-}
-
-type otelTransport struct {
-	next http.RoundTripper
-	cfg  OTelConfig
-}
-
-func (t *otelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	return t.next.RoundTrip(req)
-}
@@ -66,9 +66,9 @@ func TestNewTransport_ChainOrder_AllEnabled(t *testing.T) {
 		t.Fatalf("expected otel.next to be *retryTransport, got %T", ot.next)
 	}
 
-	bt, ok := rt.next.(*breakerTransport)
+	bt, ok := rt.next.(*BreakerTransport)
 	if !ok {
-		t.Fatalf("expected retry.next to be *breakerTransport, got %T", rt.next)
+		t.Fatalf("expected retry.next to be *BreakerTransport, got %T", rt.next)
 	}
 
 	if bt.next != base {
@@ -89,6 +89,36 @@ func TestNewClient_UsesComposedTransport(t *testing.T) {
 	}
 }
 
+func TestWithBreakerHandle_ExposesBreakerState(t *testing.T) {
+	base := &recordingRT{resp: nil, err: nil}
+	base.resp = &http.Response{
+		StatusCode: 500,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+		Header:     make(http.Header),
+	}
+
+	var handle *BreakerTransport
+	tr := NewTransport(base,
+		WithBreaker(BreakerConfig{FailureThreshold: 1, Enabled: true}),
+		WithBreakerHandle(&handle),
+	)
+
+	if handle == nil {
+		t.Fatalf("expected WithBreakerHandle to populate handle")
+	}
+
+	req, _ := http.NewRequest("GET", "http://example.com", nil)
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if snap := handle.Snapshot("example.com"); snap.Status != "open" {
+		t.Fatalf("expected breaker to report open via the exposed handle, got %s", snap.Status)
+	}
+}
+
 func TestWithBaseTransport_AppliesToClient(t *testing.T) {
 	base := &recordingRT{}
 	c := NewClient(
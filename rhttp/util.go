@@ -0,0 +1,40 @@
+package rhttp
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// drainAndClose discards (a bounded prefix of) resp's body and closes it so
+// the underlying connection can be reused by the transport's pool.
+func drainAndClose(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	resp.Body.Close()
+}
+
+// retryAfterDelay parses a Retry-After header, in either the delay-seconds
+// or HTTP-date form, and reports the duration to wait until that time.
+func retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}